@@ -0,0 +1,21 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/triplemcoder14/domainforge/cmd/domainforge/sub"
+)
+
+func main() {
+	if err := sub.RootCmd.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "[domainforge]: %v\n", err)
+
+		var daemonErr *sub.DaemonError
+		if errors.As(err, &daemonErr) {
+			os.Exit(daemonErr.Code)
+		}
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,45 @@
+package sub
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/triplemcoder14/domainforge/utils"
+)
+
+var addCmd = &cobra.Command{
+	Use:   "add <domain> [--target <spec>]...",
+	Short: "add a new domain",
+	Long: `Add a new domain to DomainForge.
+
+A --target may be repeated to bind multiple local endpoints under the
+same .local name. Each target is one of: <host>, <port>, <proto>:<port>,
+or <proto>:<host>:<port> (e.g. --target https:app:8443 --target http:app:8080).
+When no --target is given, a single plain HTTP endpoint on port 80 is used.
+
+There is no two-segment <host>:<port> form: a two-segment spec is always
+read as <proto>:<port>, so --target myhost:8080 binds port 8080 on the
+"myhost" protocol rather than host "myhost". Use the three-segment
+<proto>:<host>:<port> form to give both a host and a port.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("usage: domainforge add <domain> [--target <spec>]...")
+		}
+		targets, _ := cmd.Flags().GetStringArray("target")
+		if _, err := utils.ParseTargets(targets); err != nil {
+			return err
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "add %s", args[0])
+		for _, t := range targets {
+			fmt.Fprintf(&b, " --target %s", t)
+		}
+		return sendCommand(b.String())
+	},
+}
+
+func init() {
+	addCmd.Flags().StringArrayP("target", "t", nil, "local target spec, repeatable (<host>, <port>, <proto>:<port>, <proto>:<host>:<port>)")
+}
@@ -0,0 +1,112 @@
+package sub
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/triplemcoder14/domainforge/utils"
+)
+
+const configKeys = "qules_admin, admin_address, interface, or include_ipv6"
+
+func configField(cfg *utils.Config, key string) (string, error) {
+	switch key {
+	case "qules_admin":
+		return cfg.QulesAdmin, nil
+	case "admin_address":
+		return cfg.AdminAddress, nil
+	case "interface":
+		return cfg.Interface, nil
+	case "include_ipv6":
+		return strconv.FormatBool(cfg.IncludeIPv6), nil
+	default:
+		return "", fmt.Errorf("unknown config key %q (want %s)", key, configKeys)
+	}
+}
+
+func setConfigField(cfg *utils.Config, key, value string) error {
+	switch key {
+	case "qules_admin":
+		cfg.QulesAdmin = value
+	case "admin_address":
+		cfg.AdminAddress = value
+	case "interface":
+		cfg.Interface = value
+	case "include_ipv6":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid include_ipv6 value %q: must be true or false", value)
+		}
+		cfg.IncludeIPv6 = b
+	default:
+		return fmt.Errorf("unknown config key %q (want %s)", key, configKeys)
+	}
+	return nil
+}
+
+func configCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Get or set domainforge config values",
+		Long:  `Read or edit config.json without hand-editing the file.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get <key>",
+		Short: "Print a single config value",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("usage: domainforge config get <key>")
+			}
+			cfg, err := utils.ReadConfig()
+			if err != nil {
+				return err
+			}
+			value, err := configField(cfg, args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Println(value)
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a single config value",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return fmt.Errorf("usage: domainforge config set <key> <value>")
+			}
+			cfg, err := utils.ReadConfig()
+			if err != nil {
+				return err
+			}
+			if err := setConfigField(cfg, args[0], args[1]); err != nil {
+				return err
+			}
+			return utils.SaveConfig(cfg)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show",
+		Short: "Print the full config as JSON",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := utils.ReadConfig()
+			if err != nil {
+				return err
+			}
+			data, err := json.MarshalIndent(cfg, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		},
+	})
+
+	return cmd
+}
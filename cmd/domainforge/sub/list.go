@@ -0,0 +1,14 @@
+package sub
+
+import "github.com/spf13/cobra"
+
+func listCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List all domains",
+		Long:  `List all domains registered in DomainForge.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendCommand("list")
+		},
+	}
+}
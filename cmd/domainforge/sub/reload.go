@@ -0,0 +1,14 @@
+package sub
+
+import "github.com/spf13/cobra"
+
+func reloadCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reload",
+		Short: "Reload domainforge config",
+		Long:  `Tell the running domainforge daemon to re-read config.json and apply any changes. Registered domains are left untouched when neither the interface settings nor qules_admin changed; an interface or include_ipv6 change re-registers every domain's mDNS advertisement.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendCommand("reload")
+		},
+	}
+}
@@ -0,0 +1,21 @@
+package sub
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func removeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <domain>",
+		Short: "Remove a domain",
+		Long:  `Remove a domain from DomainForge.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("usage: domainforge remove <domain>")
+			}
+			return sendCommand(fmt.Sprintf("remove %s", args[0]))
+		},
+	}
+}
@@ -0,0 +1,130 @@
+// Package sub holds the individual domainforge CLI subcommands, one file
+// per command, wired together into RootCmd.
+package sub
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/triplemcoder14/domainforge/utils"
+)
+
+// DaemonError is returned by sendCommand/sendCommandLine when the daemon
+// reports a failure. Code is dferr's stable numeric code for the error's
+// trait, so main can exit with a status that reflects what went wrong.
+type DaemonError struct {
+	Code int
+	Msg  string
+}
+
+func (e *DaemonError) Error() string { return e.Msg }
+
+// parseDaemonLine recognizes the daemon's "ERR <code> <message>" prefix.
+func parseDaemonLine(line string) (*DaemonError, bool) {
+	rest, ok := strings.CutPrefix(line, "ERR ")
+	if !ok {
+		return nil, false
+	}
+	codeStr, msg, ok := strings.Cut(rest, " ")
+	if !ok {
+		return nil, false
+	}
+	code, err := strconv.Atoi(codeStr)
+	if err != nil {
+		return nil, false
+	}
+	return &DaemonError{Code: code, Msg: msg}, true
+}
+
+var RootCmd = &cobra.Command{
+	Use:   "domainforge",
+	Short: "domainForge is a tool for managing local domains",
+	Long:  `domainForge enables you to handle local domains along with their associated ports.`,
+}
+
+func init() {
+	RootCmd.AddCommand(addCmd)
+	RootCmd.AddCommand(startCmd)
+	RootCmd.AddCommand(stopCmd())
+	RootCmd.AddCommand(removeCmd())
+	RootCmd.AddCommand(listCmd())
+	RootCmd.AddCommand(reloadCmd())
+	RootCmd.AddCommand(statusCmd())
+	RootCmd.AddCommand(configCmd())
+}
+
+// sendCommand dials the running daemon's admin address, sends a single
+// line IPC command, and streams back its response to stdout.
+func sendCommand(command string) error {
+	cfg, err := utils.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.Dial("tcp", cfg.AdminAddress)
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %v", err)
+	}
+	defer conn.Close()
+
+	_, err = fmt.Fprintln(conn, command)
+	if err != nil {
+		return fmt.Errorf("failed to send command: %v", err)
+	}
+
+	scanner := bufio.NewScanner(conn)
+	var daemonErr *DaemonError
+	for scanner.Scan() {
+		line := scanner.Text()
+		if de, ok := parseDaemonLine(line); ok {
+			daemonErr = de
+			continue
+		}
+		fmt.Println(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading response: %v", err)
+	}
+	if daemonErr != nil {
+		return daemonErr
+	}
+
+	return nil
+}
+
+// sendCommandLine is like sendCommand but returns the daemon's first
+// response line instead of printing it, for callers that need to parse
+// structured output (e.g. `status --json`).
+func sendCommandLine(command string) (string, error) {
+	cfg, err := utils.ReadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	conn, err := net.Dial("tcp", cfg.AdminAddress)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to daemon: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := fmt.Fprintln(conn, command); err != nil {
+		return "", fmt.Errorf("failed to send command: %v", err)
+	}
+
+	scanner := bufio.NewScanner(conn)
+	if scanner.Scan() {
+		line := scanner.Text()
+		if de, ok := parseDaemonLine(line); ok {
+			return "", de
+		}
+		return line, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("error reading response: %v", err)
+	}
+	return "", nil
+}
@@ -0,0 +1,81 @@
+package sub
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/triplemcoder14/domainforge/internal/daemon"
+	"github.com/triplemcoder14/domainforge/utils"
+)
+
+var startCmd = &cobra.Command{
+	Use:   "start",
+	Short: "start the domainforge",
+	Long:  `Start the domainforge, either in the foreground or as a detached process.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		qulesAdmin, _ := cmd.Flags().GetString("qules")
+		adminAddr, _ := cmd.Flags().GetInt("addr")
+		detached, _ := cmd.Flags().GetBool("detached")
+		fresh, _ := cmd.Flags().GetBool("fresh")
+		iface, _ := cmd.Flags().GetString("interface")
+		includeIPv6, _ := cmd.Flags().GetBool("include-ipv6")
+
+		cfg := &utils.Config{
+			AdminAddress: fmt.Sprintf(":%d", adminAddr),
+			QulesAdmin:   qulesAdmin,
+			Interface:    iface,
+			IncludeIPv6:  includeIPv6,
+		}
+
+		if err := utils.SaveConfig(cfg); err != nil {
+			return fmt.Errorf("failed to save config: %v", err)
+		}
+
+		if detached {
+			// Re-exec with every flag that shaped cfg above, not just
+			// --fresh: the child parses its own flags from scratch and
+			// calls SaveConfig again, so anything left at its default here
+			// would silently overwrite the config the parent just saved.
+			detachedArgs := []string{
+				"start",
+				"--addr", strconv.Itoa(adminAddr),
+				"--qules", qulesAdmin,
+			}
+			if fresh {
+				detachedArgs = append(detachedArgs, "--fresh")
+			}
+			if iface != "" {
+				detachedArgs = append(detachedArgs, "--interface", iface)
+			}
+			if includeIPv6 {
+				detachedArgs = append(detachedArgs, "--include-ipv6")
+			}
+			cmd := exec.Command(os.Args[0], detachedArgs...)
+			cmd.Stdout = nil
+			cmd.Stderr = nil
+			cmd.Stdin = nil
+			cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+			if err := cmd.Start(); err != nil {
+				return fmt.Errorf("failed to start in detached mode: %v", err)
+			}
+
+			return nil
+		}
+
+		daemon.Run(cfg, fresh)
+		return nil
+	},
+}
+
+func init() {
+	startCmd.Flags().IntP("addr", "a", 2013, "domainforge process address")
+	startCmd.Flags().StringP("qules", "c", "http://localhost:1990", "local qules admin address")
+	startCmd.Flags().BoolP("detached", "d", false, "run domainforge in background")
+	startCmd.Flags().Bool("fresh", false, "ignore any state.json from a prior run")
+	startCmd.Flags().String("interface", "", "only broadcast on this named interface (default: every suitable interface)")
+	startCmd.Flags().Bool("include-ipv6", false, "also advertise global-scope IPv6 addresses")
+}
@@ -0,0 +1,56 @@
+package sub
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/triplemcoder14/domainforge/internal/daemon"
+)
+
+func statusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the status of every registered domain",
+		Long:  `Report, per domain, its service name, host, bound ports, time since the last successful broadcast, and its last error, if any.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			line, err := sendCommandLine("status")
+			if err != nil {
+				return err
+			}
+
+			var entries []daemon.StatusEntry
+			if err := json.Unmarshal([]byte(line), &entries); err != nil {
+				return fmt.Errorf("failed to parse status response: %v", err)
+			}
+
+			asJSON, _ := cmd.Flags().GetBool("json")
+			if asJSON {
+				fmt.Println(line)
+				return nil
+			}
+
+			if len(entries) == 0 {
+				fmt.Println("No domains registered")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "DOMAIN\tSERVICE\tHOST\tPORTS\tSINCE LAST SUCCESS\tLAST ERROR")
+			for _, e := range entries {
+				ports := make([]string, 0, len(e.Ports))
+				for _, p := range e.Ports {
+					ports = append(ports, fmt.Sprintf("%d", p))
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+					e.Domain, e.Service, e.Host, strings.Join(ports, ","), e.SinceLastSuccess, e.LastError)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().Bool("json", false, "output the raw JSON status report")
+	return cmd
+}
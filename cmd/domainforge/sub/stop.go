@@ -0,0 +1,14 @@
+package sub
+
+import "github.com/spf13/cobra"
+
+func stopCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop",
+		Short: "Stop domainforge daemon",
+		Long:  `Stop the running domainforge daemon.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendCommand("stop")
+		},
+	}
+}
@@ -0,0 +1,446 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oleksandr/bonjour"
+	"github.com/triplemcoder14/domainforge/internal/dferr"
+	"github.com/triplemcoder14/domainforge/utils"
+)
+
+const (
+	minBackoff = 1 * time.Second
+	maxBackoff = 30 * time.Second
+)
+
+type Record struct {
+	clean         string
+	service       string
+	host          string
+	servers       []*bonjour.Server
+	targets       []*utils.Target
+	lastBroadcast time.Time
+	lastErr       error
+
+	// failed is set once a non-NetworkTransient (permanent) error is hit;
+	// the record stops being retried by broadcastAll. registerAll has
+	// already shut down its earlier servers by then, so it is no longer
+	// advertised.
+	failed bool
+	// backoff/nextRetry implement the NetworkTransient retry schedule:
+	// on failure backoff doubles (capped at maxBackoff) and the record is
+	// skipped until nextRetry.
+	backoff   time.Duration
+	nextRetry time.Time
+}
+
+// StatusEntry is a structured, per-domain snapshot of a Record suitable for
+// the CLI's `status` command to render as a table or as JSON.
+type StatusEntry struct {
+	Domain           string `json:"domain"`
+	Service          string `json:"service"`
+	Host             string `json:"host"`
+	Ports            []int  `json:"ports"`
+	SinceLastSuccess string `json:"since_last_success"`
+	LastError        string `json:"last_error,omitempty"`
+}
+
+type DomainForge struct {
+	records map[string]*Record
+	cfg     *utils.Config
+	mu      sync.Mutex
+}
+
+func NewDomainForge(cfg *utils.Config) *DomainForge {
+	return &DomainForge{
+		records: make(map[string]*Record),
+		cfg:     cfg,
+	}
+}
+
+func (df *DomainForge) List() []string {
+	df.mu.Lock()
+	defer df.mu.Unlock()
+
+	domains := make([]string, 0, len(df.records))
+	for domain := range df.records {
+		domains = append(domains, domain)
+	}
+	return domains
+}
+
+// Status returns a structured report of every registered domain: its
+// service name, host, bound ports, time since its last successful
+// registration (by Add or a broadcastAll pass), and its last error, if any.
+func (df *DomainForge) Status() []StatusEntry {
+	df.mu.Lock()
+	defer df.mu.Unlock()
+
+	entries := make([]StatusEntry, 0, len(df.records))
+	for domain, rec := range df.records {
+		ports := make([]int, 0, len(rec.targets))
+		for _, t := range rec.targets {
+			ports = append(ports, t.Port)
+		}
+
+		entry := StatusEntry{
+			Domain:  domain,
+			Service: rec.service,
+			Host:    rec.host,
+			Ports:   ports,
+		}
+		if !rec.lastBroadcast.IsZero() {
+			entry.SinceLastSuccess = time.Since(rec.lastBroadcast).Round(time.Second).String()
+		}
+		if rec.lastErr != nil {
+			entry.LastError = rec.lastErr.Error()
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func (df *DomainForge) Add(domain string, targets []*utils.Target) error {
+	df.mu.Lock()
+	defer df.mu.Unlock()
+
+	return df.addLocked(domain, targets, false)
+}
+
+// restoreLocked replays a single persisted StateEntry through the normal
+// registration path on daemon startup, skipping the "already registered"
+// check since a freshly-started daemon's records map is always empty for
+// its own restored rows.
+func (df *DomainForge) restoreLocked(domain string, targets []*utils.Target) error {
+	return df.addLocked(domain, targets, true)
+}
+
+func (df *DomainForge) addLocked(domain string, targets []*utils.Target, restoring bool) error {
+	addrs, err := utils.GetLocalIPs(utils.IPOptions{Interface: df.cfg.Interface, IncludeIPv6: df.cfg.IncludeIPv6})
+	if err != nil {
+		return err
+	}
+
+	clean := strings.TrimSpace(domain)
+	fullDomain := fmt.Sprintf("%s.local", clean)
+	if _, exists := df.records[fullDomain]; exists && !restoring {
+		return dferr.Newf(dferr.RegistrationConflict, "domain %s already registered", fullDomain)
+	}
+	fullHost := fmt.Sprintf("%s.", fullDomain)
+
+	if len(targets) == 0 {
+		targets = []*utils.Target{{Proto: "http", Port: 80}}
+	}
+
+	rec := &Record{
+		clean:   clean,
+		service: fmt.Sprintf("_%s._tcp", clean),
+		host:    fullHost,
+		targets: targets,
+	}
+
+	// Register one bonjour proxy per (proto, port) binding per selected
+	// interface/address, rolling back everything already registered if a
+	// later binding fails.
+	servers, err := registerAll(rec, addrs)
+	if err != nil {
+		return err
+	}
+	rec.servers = servers
+
+	// All bindings go into a single Qules server block request, added only
+	// once every bonjour registration above has already succeeded. That
+	// keeps the Qules side atomic from our point of view (one call, one
+	// outcome), so a failure here only ever has to roll back bonjour.
+	if err := addQulesServerBlock([]string{fullDomain}, targets, df.cfg.QulesAdmin); err != nil {
+		for _, s := range servers {
+			s.Shutdown()
+		}
+		return err
+	}
+
+	rec.lastBroadcast = time.Now()
+	df.records[fullDomain] = rec
+
+	if err := df.persistLocked(); err != nil {
+		log.Printf("Error persisting state after adding %s: %v", fullDomain, err)
+	}
+	return nil
+}
+
+func (df *DomainForge) Remove(domain string) error {
+	df.mu.Lock()
+	defer df.mu.Unlock()
+
+	record, exists := df.records[domain]
+	if !exists {
+		return fmt.Errorf("domain %s not registered", domain)
+	}
+
+	for _, s := range record.servers {
+		s.Shutdown()
+	}
+	if err := removeQulesServerBlock([]string{domain}, record.targets, df.cfg.QulesAdmin); err != nil {
+		log.Printf("Error removing Qules server block for %s: %v", domain, err)
+	}
+	delete(df.records, domain)
+	log.Printf("Removed domain: %s", domain)
+
+	if err := df.persistLocked(); err != nil {
+		log.Printf("Error persisting state after removing %s: %v", domain, err)
+	}
+	return nil
+}
+
+// persistLocked writes state.json from the current records map. Callers
+// must hold df.mu.
+func (df *DomainForge) persistLocked() error {
+	entries := make([]utils.StateEntry, 0, len(df.records))
+	for _, rec := range df.records {
+		entries = append(entries, utils.StateEntry{
+			Domain:                   rec.clean,
+			Targets:                  rec.targets,
+			Service:                  rec.service,
+			Host:                     rec.host,
+			QulesAdminAtRegistration: df.cfg.QulesAdmin,
+		})
+	}
+	return utils.SaveState(entries)
+}
+
+// Restore replays every entry in state.json through the normal Add path,
+// bringing back mDNS advertisements and Qules blocks that existed before
+// the daemon last stopped.
+func (df *DomainForge) Restore() error {
+	df.mu.Lock()
+	defer df.mu.Unlock()
+
+	entries, err := utils.LoadState()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := df.restoreLocked(entry.Domain, entry.Targets); err != nil {
+			log.Printf("Error restoring domain %s: %v", entry.Domain, err)
+		}
+	}
+	return nil
+}
+
+func (df *DomainForge) Shutdown() {
+	df.mu.Lock()
+	defer df.mu.Unlock()
+
+	for domain, rec := range df.records {
+		for _, s := range rec.servers {
+			s.Shutdown()
+		}
+		log.Printf("Shutting down domain: %s", domain)
+	}
+
+	// The records map is not cleared, so state.json is rewritten with
+	// every domain still in it and they come back via Restore on the
+	// next start.
+	if err := df.persistLocked(); err != nil {
+		log.Printf("Error persisting state on shutdown: %v", err)
+	}
+}
+
+// Reload re-reads config.json, diffs it against the config the daemon is
+// currently running with, and re-registers only the records affected by
+// what actually changed: an Interface/IncludeIPv6 change re-runs bonjour
+// registration (the address set GetLocalIPs returns depends on it), and a
+// QulesAdmin change re-adds every record's Qules server block at the new
+// address. A reload that changes neither leaves every record untouched
+// instead of waiting on the next broadcastAll tick to catch up.
+func (df *DomainForge) Reload() (*utils.Config, error) {
+	df.mu.Lock()
+	defer df.mu.Unlock()
+
+	newCfg, err := utils.ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	oldCfg := df.cfg
+	df.cfg = newCfg
+
+	ifaceChanged := oldCfg.Interface != newCfg.Interface || oldCfg.IncludeIPv6 != newCfg.IncludeIPv6
+	qulesChanged := oldCfg.QulesAdmin != newCfg.QulesAdmin
+
+	if !ifaceChanged && !qulesChanged {
+		log.Printf("reload: config unchanged, nothing to re-register")
+		return newCfg, nil
+	}
+
+	if ifaceChanged {
+		addrs, err := utils.GetLocalIPs(utils.IPOptions{Interface: newCfg.Interface, IncludeIPv6: newCfg.IncludeIPv6})
+		if err != nil {
+			log.Printf("warn: reload: failed to list interfaces, leaving bonjour registrations as-is: %v", err)
+		} else {
+			for domain, rec := range df.records {
+				servers, err := registerAll(rec, addrs)
+				if err != nil {
+					log.Printf("error: reload: failed to re-register %s: %v", domain, err)
+					continue
+				}
+				rec.servers = servers
+			}
+		}
+	}
+
+	if qulesChanged {
+		for domain, rec := range df.records {
+			if err := addQulesServerBlock([]string{domain}, rec.targets, newCfg.QulesAdmin); err != nil {
+				log.Printf("error: reload: failed to re-add Qules block for %s at new qules_admin: %v", domain, err)
+			}
+		}
+	}
+
+	log.Printf("reload: re-registered %d domain(s) (interface changed: %t, qules_admin changed: %t)", len(df.records), ifaceChanged, qulesChanged)
+	return newCfg, nil
+}
+
+func (df *DomainForge) startBroadcast(ctx context.Context) {
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			df.broadcastAll()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// registerAll (re-)registers every (target, address) combination for a
+// single record, shutting down whatever servers it previously held. It is
+// the one place that talks to bonjour.RegisterProxy for an existing
+// record, used by both Add and broadcastAll's ticker path, and is what
+// puts a domain on every selected interface/address instead of just one.
+func registerAll(rec *Record, addrs []utils.LocalAddr) ([]*bonjour.Server, error) {
+	for _, s := range rec.servers {
+		s.Shutdown()
+	}
+
+	servers := make([]*bonjour.Server, 0, len(rec.targets)*len(addrs))
+	rollback := func() {
+		for _, s := range servers {
+			s.Shutdown()
+		}
+	}
+
+	for _, target := range rec.targets {
+		service := fmt.Sprintf("_%s-%s._tcp", rec.clean, target.Proto)
+		for _, addr := range addrs {
+			server, err := bonjour.RegisterProxy(
+				"domainforge",
+				service,
+				"",
+				target.Port,
+				rec.host,
+				addr.IP,
+				[]string{},
+				nil)
+			if err != nil {
+				rollback()
+				msg := fmt.Sprintf("failed to register %s binding on port %d (%s/%s)", target.Proto, target.Port, addr.Interface, addr.IP)
+				return nil, dferr.Wrap(err, classifyBonjourErr(err), msg)
+			}
+			servers = append(servers, server)
+		}
+	}
+	return servers, nil
+}
+
+// classifyBonjourErr decides whether a bonjour.RegisterProxy failure is
+// worth retrying. Bonjour rejects malformed call arguments (a missing
+// instance/service name, a zero port, a host/IP it can't parse) before it
+// ever touches the network; those come from how we built the call and will
+// fail exactly the same way on every retry, so they're ConfigInvalid.
+// Everything else - binding the shared UDP socket, joining the multicast
+// group - is a runtime network condition that can clear up on its own, so
+// it stays NetworkTransient.
+func classifyBonjourErr(err error) dferr.Trait {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "Missing "),
+		strings.Contains(msg, "Failed to parse given IP"),
+		strings.Contains(msg, "neither IPv4 nor IPv6"),
+		strings.Contains(msg, "Could not determine"):
+		return dferr.ConfigInvalid
+	default:
+		return dferr.NetworkTransient
+	}
+}
+
+// nextBackoff returns the backoff to wait before the next retry given the
+// current one: minBackoff the first time (current == 0), doubling on every
+// subsequent call and capped at maxBackoff.
+func nextBackoff(current time.Duration) time.Duration {
+	if current == 0 {
+		return minBackoff
+	}
+	if current >= maxBackoff {
+		return maxBackoff
+	}
+	doubled := current * 2
+	if doubled > maxBackoff {
+		return maxBackoff
+	}
+	return doubled
+}
+
+// broadcastAll refreshes every record's mDNS registration. A
+// NetworkTransient failure backs that single record off exponentially
+// (capped at maxBackoff); records are only checked on the 15s tick, so a
+// backoff shorter than the tick still retries on the next tick. Any other
+// trait (see classifyBonjourErr) marks the record failed; registerAll has
+// already shut down its earlier servers, so it stops being advertised,
+// without affecting the rest of the map.
+func (df *DomainForge) broadcastAll() {
+	df.mu.Lock()
+	defer df.mu.Unlock()
+
+	addrs, err := utils.GetLocalIPs(utils.IPOptions{Interface: df.cfg.Interface, IncludeIPv6: df.cfg.IncludeIPv6})
+	if err != nil {
+		log.Printf("warn: skipping broadcast pass, %v", err)
+		return
+	}
+
+	now := time.Now()
+	for domain, rec := range df.records {
+		if rec.failed || now.Before(rec.nextRetry) {
+			continue
+		}
+
+		servers, err := registerAll(rec, addrs)
+		if err != nil {
+			rec.lastErr = err
+			switch {
+			case dferr.Is(err, dferr.NetworkTransient):
+				rec.backoff = nextBackoff(rec.backoff)
+				rec.nextRetry = now.Add(rec.backoff)
+				log.Printf("warn: %s: %v (retrying in %s)", domain, err, rec.backoff)
+			default:
+				rec.failed = true
+				log.Printf("error: %s: %v (marking record failed)", domain, err)
+			}
+			continue
+		}
+
+		rec.servers = servers
+		rec.backoff = 0
+		rec.nextRetry = time.Time{}
+		rec.lastErr = nil
+		rec.lastBroadcast = now
+	}
+}
@@ -0,0 +1,30 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextBackoffStartsAtMinimum(t *testing.T) {
+	if got := nextBackoff(0); got != minBackoff {
+		t.Errorf("nextBackoff(0) = %s, want %s", got, minBackoff)
+	}
+}
+
+func TestNextBackoffDoubles(t *testing.T) {
+	got := nextBackoff(minBackoff)
+	want := minBackoff * 2
+	if got != want {
+		t.Errorf("nextBackoff(%s) = %s, want %s", minBackoff, got, want)
+	}
+}
+
+func TestNextBackoffCapsAtMaximum(t *testing.T) {
+	current := maxBackoff - time.Second
+	if got := nextBackoff(current); got != maxBackoff {
+		t.Errorf("nextBackoff(%s) = %s, want capped at %s", current, got, maxBackoff)
+	}
+	if got := nextBackoff(maxBackoff); got != maxBackoff {
+		t.Errorf("nextBackoff(%s) = %s, want it to stay at %s", maxBackoff, got, maxBackoff)
+	}
+}
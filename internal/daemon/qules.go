@@ -0,0 +1,94 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/triplemcoder14/domainforge/internal/dferr"
+	"github.com/triplemcoder14/domainforge/utils"
+)
+
+const qulesRequestTimeout = 5 * time.Second
+
+var qulesHTTPClient = &http.Client{Timeout: qulesRequestTimeout}
+
+type qulesBinding struct {
+	Proto string `json:"proto"`
+	Port  int    `json:"port"`
+}
+
+type qulesServerBlockRequest struct {
+	Domains  []string       `json:"domains"`
+	Bindings []qulesBinding `json:"bindings"`
+}
+
+func qulesBindings(targets []*utils.Target) []qulesBinding {
+	bindings := make([]qulesBinding, 0, len(targets))
+	for _, t := range targets {
+		bindings = append(bindings, qulesBinding{Proto: t.Proto, Port: t.Port})
+	}
+	return bindings
+}
+
+// addQulesServerBlock asks the Qules admin API to add a single server
+// block covering every (proto, port) binding in targets for domains, in
+// one request. Batching every binding into one call keeps the add atomic
+// from the caller's point of view: it either takes effect as a whole or
+// not at all, so addLocked never has to unwind a half-applied block.
+func addQulesServerBlock(domains []string, targets []*utils.Target, qulesAdmin string) error {
+	return doQulesServerBlockRequest(http.MethodPost, domains, targets, qulesAdmin)
+}
+
+// removeQulesServerBlock is the inverse of addQulesServerBlock, used when
+// a domain is removed so its Qules routing doesn't outlive its mDNS
+// advertisement.
+func removeQulesServerBlock(domains []string, targets []*utils.Target, qulesAdmin string) error {
+	return doQulesServerBlockRequest(http.MethodDelete, domains, targets, qulesAdmin)
+}
+
+func doQulesServerBlockRequest(method string, domains []string, targets []*utils.Target, qulesAdmin string) error {
+	body, err := json.Marshal(qulesServerBlockRequest{
+		Domains:  domains,
+		Bindings: qulesBindings(targets),
+	})
+	if err != nil {
+		return dferr.Wrap(err, dferr.ConfigInvalid, "failed to encode Qules server block request")
+	}
+
+	req, err := http.NewRequest(method, qulesAdmin+"/server_blocks", bytes.NewReader(body))
+	if err != nil {
+		return dferr.Wrap(err, dferr.ConfigInvalid, "invalid qules_admin address")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := qulesHTTPClient.Do(req)
+	if err != nil {
+		return dferr.Wrap(err, dferr.NetworkTransient, "failed to reach Qules admin API")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return dferr.Newf(classifyQulesStatus(resp.StatusCode), "Qules admin API returned %s", resp.Status)
+	}
+	return nil
+}
+
+// classifyQulesStatus maps a non-2xx Qules admin response to the trait a
+// caller should treat it as. A 409 means a server block already exists for
+// this binding, which is the same permanent condition as a local "already
+// registered" record; any other 4xx means the request itself was bad
+// (malformed body, unknown domain) and retrying it unchanged will fail
+// identically every time. Only 3xx/5xx - a redirect we didn't expect, or
+// the admin API itself misbehaving - are worth retrying.
+func classifyQulesStatus(statusCode int) dferr.Trait {
+	switch {
+	case statusCode == http.StatusConflict:
+		return dferr.RegistrationConflict
+	case statusCode >= 400 && statusCode < 500:
+		return dferr.ConfigInvalid
+	default:
+		return dferr.NetworkTransient
+	}
+}
@@ -0,0 +1,176 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/triplemcoder14/domainforge/internal/dferr"
+	"github.com/triplemcoder14/domainforge/utils"
+)
+
+// writeErr reports err to the client as "ERR <code> <message>", where code
+// is dferr's stable numeric code for err's trait (1 for an untyped error).
+// The CLI parses this prefix to decide whether to exit non-zero.
+func writeErr(conn net.Conn, err error) {
+	fmt.Fprintf(conn, "ERR %d %v\n", dferr.Code(err), err)
+}
+
+// Run starts the domainforge daemon: it listens for IPC connections on
+// cfg.AdminAddress and keeps every registered domain broadcast over mDNS
+// until it receives an interrupt or a "stop" command. Unless fresh is
+// true, any domains persisted in state.json from a prior run are replayed
+// before the daemon starts serving.
+func Run(cfg *utils.Config, fresh bool) {
+	df := NewDomainForge(cfg)
+
+	if !fresh {
+		if err := df.Restore(); err != nil {
+			log.Printf("Error restoring prior state: %v", err)
+		}
+	}
+
+	listener, err := net.Listen("tcp", cfg.AdminAddress)
+	if err != nil {
+		log.Fatalf("failed to start domainforge server: %v", err)
+	}
+	defer listener.Close()
+
+	log.Println("domainForge server started. listening on", cfg.AdminAddress)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go df.startBroadcast(ctx)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+		<-c
+		cancel()
+	}()
+
+	doneChan := make(chan struct{})
+	connections := make(chan net.Conn)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+					log.Printf("error accepting connection: %v\n", err)
+					continue
+				}
+			}
+
+			select {
+			case connections <- conn:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case conn := <-connections:
+			go handleConnection(doneChan, conn, df)
+		case <-doneChan:
+			cancel()
+		case <-ctx.Done():
+			log.Println("shutting down domainforge")
+			df.Shutdown()
+			return
+		}
+	}
+}
+
+func handleConnection(ch chan struct{}, conn net.Conn, df *DomainForge) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	if scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		cmd := parts[0]
+		switch cmd {
+		case "add":
+			if len(parts) < 2 {
+				fmt.Fprintln(conn, "Invalid command. Usage: add <domain> [--target <spec>]...")
+				return
+			}
+			domain := parts[1]
+
+			var specs []string
+			rest := parts[2:]
+			for i := 0; i < len(rest); i++ {
+				if rest[i] != "--target" || i+1 >= len(rest) {
+					fmt.Fprintln(conn, "Invalid command. Usage: add <domain> [--target <spec>]...")
+					return
+				}
+				specs = append(specs, rest[i+1])
+				i++
+			}
+
+			targets, err := utils.ParseTargets(specs)
+			if err != nil {
+				writeErr(conn, err)
+				return
+			}
+
+			if err := df.Add(domain, targets); err != nil {
+				writeErr(conn, err)
+			} else {
+				fmt.Fprintf(conn, "Added domain: %s with %d target(s)\n", domain, len(targets))
+			}
+		case "remove":
+			if len(parts) != 2 {
+				fmt.Fprintln(conn, "Invalid command. Usage: remove <domain>")
+				return
+			}
+			domain := parts[1]
+			err := df.Remove(domain)
+			if err != nil {
+				writeErr(conn, err)
+			} else {
+				fmt.Fprintf(conn, "Removed domain: %s\n", domain)
+			}
+		case "list":
+			domains := df.List()
+			if len(domains) == 0 {
+				fmt.Fprintln(conn, "No domains registered")
+			} else {
+				fmt.Fprintln(conn, "Registered domains:")
+				for _, domain := range domains {
+					fmt.Fprintf(conn, "- %s\n", domain)
+				}
+			}
+		case "status":
+			entries := df.Status()
+			data, err := json.Marshal(entries)
+			if err != nil {
+				writeErr(conn, err)
+				return
+			}
+			fmt.Fprintln(conn, string(data))
+		case "reload":
+			newCfg, err := df.Reload()
+			if err != nil {
+				writeErr(conn, err)
+				return
+			}
+			fmt.Fprintf(conn, "Reloaded config. qules_admin: %s\n", newCfg.QulesAdmin)
+		case "stop":
+			close(ch)
+		default:
+			fmt.Fprintln(conn, "Unknown command")
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package dferr is domainforge's typed error hierarchy. Library code
+// throughout the daemon raises namespaced traits instead of calling
+// log.Fatalln, so a network blip or a single bad registration no longer
+// has to take down the whole process.
+package dferr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Trait namespaces an Error the way errorx traits do: callers can test
+// "is this a NetworkTransient problem?" without caring about the exact
+// message, and decide whether to retry or give up accordingly.
+type Trait struct {
+	name string
+	code int
+}
+
+func (t Trait) String() string { return t.name }
+
+var (
+	// NetworkTransient covers blips expected to clear on their own: a
+	// dropped interface, a momentary mDNS registration failure, an
+	// unreachable Qules admin endpoint. Callers should retry with backoff.
+	NetworkTransient = Trait{name: "network_transient", code: 10}
+
+	// RegistrationConflict covers a domain (or binding) that is already
+	// registered. It is permanent until the conflicting record is removed.
+	RegistrationConflict = Trait{name: "registration_conflict", code: 11}
+
+	// ConfigInvalid covers malformed or missing configuration (a target
+	// spec that won't parse, a config.json that won't unmarshal).
+	ConfigInvalid = Trait{name: "config_invalid", code: 12}
+)
+
+// Error is a dferr-flavored error: a trait, a message, and an optional
+// wrapped cause.
+type Error struct {
+	trait Trait
+	msg   string
+	cause error
+}
+
+func New(trait Trait, msg string) *Error {
+	return &Error{trait: trait, msg: msg}
+}
+
+func Newf(trait Trait, format string, args ...any) *Error {
+	return &Error{trait: trait, msg: fmt.Sprintf(format, args...)}
+}
+
+// Wrap attaches a trait and message to cause, preserving it for Unwrap/Is.
+func Wrap(cause error, trait Trait, msg string) *Error {
+	return &Error{trait: trait, msg: msg, cause: cause}
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%s: %v", e.msg, e.cause)
+	}
+	return e.msg
+}
+
+func (e *Error) Unwrap() error { return e.cause }
+
+func (e *Error) Trait() Trait { return e.trait }
+
+// Is reports whether err, or any error in its cause chain, carries trait.
+func Is(err error, trait Trait) bool {
+	for err != nil {
+		var de *Error
+		if errors.As(err, &de) && de.trait == trait {
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+// Code returns the stable numeric code for err's trait, or 1 for an error
+// that isn't a *Error at all, so callers (like the CLI) can exit non-zero
+// with a consistent status.
+func Code(err error) int {
+	var de *Error
+	if errors.As(err, &de) {
+		return de.trait.code
+	}
+	return 1
+}
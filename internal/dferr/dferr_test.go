@@ -0,0 +1,62 @@
+package dferr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsMatchesWrappedTrait(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := Wrap(cause, NetworkTransient, "failed to reach Qules admin API")
+
+	if !Is(err, NetworkTransient) {
+		t.Errorf("Is(err, NetworkTransient) = false, want true")
+	}
+	if Is(err, ConfigInvalid) {
+		t.Errorf("Is(err, ConfigInvalid) = true, want false")
+	}
+}
+
+func TestIsWalksUnwrapChain(t *testing.T) {
+	inner := New(RegistrationConflict, "domain already registered")
+	outer := Wrap(inner, NetworkTransient, "retry failed")
+
+	if !Is(outer, NetworkTransient) {
+		t.Errorf("Is(outer, NetworkTransient) = false, want true")
+	}
+	if !Is(outer, RegistrationConflict) {
+		t.Errorf("Is(outer, RegistrationConflict) = false, want true (should walk the cause chain)")
+	}
+}
+
+func TestIsFalseForPlainError(t *testing.T) {
+	if Is(errors.New("plain"), NetworkTransient) {
+		t.Errorf("Is(plain error, NetworkTransient) = true, want false")
+	}
+}
+
+func TestCode(t *testing.T) {
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{New(NetworkTransient, "x"), 10},
+		{New(RegistrationConflict, "x"), 11},
+		{New(ConfigInvalid, "x"), 12},
+		{errors.New("plain"), 1},
+	}
+
+	for _, c := range cases {
+		if got := Code(c.err); got != c.want {
+			t.Errorf("Code(%v) = %d, want %d", c.err, got, c.want)
+		}
+	}
+}
+
+func TestErrorMessageIncludesCause(t *testing.T) {
+	err := Wrap(errors.New("dial tcp: timeout"), NetworkTransient, "failed to register binding")
+	want := "failed to register binding: dial tcp: timeout"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// StateEntry is one previously-registered domain, persisted so the daemon
+// can replay it on the next restart instead of silently dropping it.
+type StateEntry struct {
+	Domain                   string    `json:"domain"`
+	Targets                  []*Target `json:"targets"`
+	Service                  string    `json:"service"`
+	Host                     string    `json:"host"`
+	QulesAdminAtRegistration string    `json:"qules_admin_at_registration"`
+}
+
+func stateFilePath() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "state.json"), nil
+}
+
+// LoadState reads state.json, returning an empty slice if it does not yet
+// exist.
+func LoadState() ([]StateEntry, error) {
+	path, err := stateFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []StateEntry{}, nil
+		}
+		return nil, err
+	}
+
+	var entries []StateEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// SaveState writes state.json atomically: the new content is written to a
+// temp file in the same directory and then renamed into place, so a reader
+// (or a crash mid-write) never observes a partial file.
+func SaveState(entries []StateEntry) error {
+	path, err := stateFilePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// withTempConfigDir points GetConfigDir's underlying home directory at a
+// temp dir for the duration of the test, so SaveState/LoadState never touch
+// a real user config directory.
+func withTempConfigDir(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	// homedir.Dir caches the first home it finds; clear it so this test's
+	// HOME is picked up, and again afterwards so it does not outlive the test.
+	homedir.Reset()
+	t.Cleanup(homedir.Reset)
+}
+
+func TestSaveStateThenLoadStateRoundTrips(t *testing.T) {
+	withTempConfigDir(t)
+
+	entries := []StateEntry{
+		{
+			Domain:                   "example",
+			Targets:                  []*Target{{Proto: "http", Port: 8080}},
+			Service:                  "_example._tcp",
+			Host:                     "example.local.",
+			QulesAdminAtRegistration: "http://localhost:1990",
+		},
+	}
+
+	if err := SaveState(entries); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+
+	got, err := LoadState()
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if len(got) != 1 || got[0].Domain != "example" || got[0].Targets[0].Port != 8080 {
+		t.Fatalf("LoadState = %+v, want one round-tripped entry for %+v", got, entries[0])
+	}
+}
+
+func TestLoadStateMissingFileReturnsEmpty(t *testing.T) {
+	withTempConfigDir(t)
+
+	entries, err := LoadState()
+	if err != nil {
+		t.Fatalf("LoadState on a missing state.json returned error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("LoadState on a missing state.json = %+v, want empty", entries)
+	}
+}
+
+func TestSaveStateWritesAtomically(t *testing.T) {
+	withTempConfigDir(t)
+
+	if err := SaveState([]StateEntry{{Domain: "example"}}); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+
+	path, err := stateFilePath()
+	if err != nil {
+		t.Fatalf("stateFilePath: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("SaveState left a temp file behind at %s.tmp", path)
+	}
+	if _, err := os.Stat(filepath.Dir(path)); err != nil {
+		t.Fatalf("SaveState did not create the config dir: %v", err)
+	}
+}
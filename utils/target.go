@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/triplemcoder14/domainforge/internal/dferr"
+)
+
+// Target describes a single local endpoint a domain should be bound to:
+// a protocol (e.g. http, https, or any other name given in the spec), an
+// optional host override, and a port.
+type Target struct {
+	Proto string
+	Host  string
+	Port  int
+}
+
+func defaultPortFor(proto string) int {
+	if proto == "https" {
+		return 443
+	}
+	return 80
+}
+
+// ParseTarget parses a local target specification of the form `<host>`,
+// `<port>`, `<proto>:<port>`, or `<proto>:<host>:<port>` into a Target.
+// The proto is lowercased, surrounding slashes are trimmed, and default
+// ports (80 for http, 443 for https) are inferred when only a proto or
+// only a host is given.
+//
+// There is no dedicated `<host>:<port>` form: a two-segment spec is always
+// read as `<proto>:<port>`, so "myhost:8080" parses as proto "myhost" on
+// port 8080, not host "myhost". Callers that need both a host and a port
+// must use the three-segment `<proto>:<host>:<port>` form.
+func ParseTarget(spec string) (*Target, error) {
+	trimmed := strings.Trim(strings.TrimSpace(spec), "/")
+	if trimmed == "" {
+		return nil, dferr.New(dferr.ConfigInvalid, "empty target specification")
+	}
+
+	parts := strings.Split(trimmed, ":")
+	if len(parts) > 3 {
+		return nil, dferr.Newf(dferr.ConfigInvalid, "invalid target %q: too many colon-separated segments", spec)
+	}
+	if parts[0] == "" {
+		return nil, dferr.Newf(dferr.ConfigInvalid, "invalid target %q: empty first segment", spec)
+	}
+
+	switch len(parts) {
+	case 1:
+		token := parts[0]
+		if proto := strings.ToLower(token); proto == "http" || proto == "https" {
+			return &Target{Proto: proto, Port: defaultPortFor(proto)}, nil
+		}
+		if port, err := strconv.Atoi(token); err == nil {
+			return &Target{Proto: "http", Port: port}, nil
+		}
+		return &Target{Proto: "http", Host: token, Port: defaultPortFor("http")}, nil
+
+	case 2:
+		proto := strings.ToLower(parts[0])
+		port, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, dferr.Newf(dferr.ConfigInvalid, "invalid target %q: port %q is not a number", spec, parts[1])
+		}
+		return &Target{Proto: proto, Port: port}, nil
+
+	default: // len(parts) == 3
+		proto := strings.ToLower(parts[0])
+		host := parts[1]
+		port, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return nil, dferr.Newf(dferr.ConfigInvalid, "invalid target %q: port %q is not a number", spec, parts[2])
+		}
+		return &Target{Proto: proto, Host: host, Port: port}, nil
+	}
+}
+
+// ParseTargets parses a repeatable list of local target specifications,
+// defaulting to a single plain HTTP target on port 80 when none are given.
+func ParseTargets(specs []string) ([]*Target, error) {
+	if len(specs) == 0 {
+		return []*Target{{Proto: "http", Port: 80}}, nil
+	}
+
+	targets := make([]*Target, 0, len(specs))
+	for _, spec := range specs {
+		target, err := ParseTarget(spec)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/triplemcoder14/domainforge/internal/dferr"
+)
+
+func TestParseTarget(t *testing.T) {
+	cases := []struct {
+		spec string
+		want Target
+	}{
+		{"http", Target{Proto: "http", Port: 80}},
+		{"https", Target{Proto: "https", Port: 443}},
+		{"8080", Target{Proto: "http", Port: 8080}},
+		{"localhost", Target{Proto: "http", Host: "localhost", Port: 80}},
+		{"https:8443", Target{Proto: "https", Port: 8443}},
+		{"HTTPS:8443", Target{Proto: "https", Port: 8443}},
+		{"tcp:db.local:5432", Target{Proto: "tcp", Host: "db.local", Port: 5432}},
+		{"/http/", Target{Proto: "http", Port: 80}},
+	}
+
+	for _, c := range cases {
+		got, err := ParseTarget(c.spec)
+		if err != nil {
+			t.Errorf("ParseTarget(%q) returned error: %v", c.spec, err)
+			continue
+		}
+		if *got != c.want {
+			t.Errorf("ParseTarget(%q) = %+v, want %+v", c.spec, *got, c.want)
+		}
+	}
+}
+
+func TestParseTargetInvalid(t *testing.T) {
+	cases := []string{
+		"",
+		"/",
+		":8080",
+		"http:notaport",
+		"proto:host:notaport",
+		"a:b:c:d",
+	}
+
+	for _, spec := range cases {
+		_, err := ParseTarget(spec)
+		if err == nil {
+			t.Errorf("ParseTarget(%q) expected an error, got nil", spec)
+			continue
+		}
+		if !dferr.Is(err, dferr.ConfigInvalid) {
+			t.Errorf("ParseTarget(%q) error %v is not ConfigInvalid", spec, err)
+		}
+	}
+}
+
+func TestParseTargetsEmptyDefaultsToPlainHTTP(t *testing.T) {
+	targets, err := ParseTargets(nil)
+	if err != nil {
+		t.Fatalf("ParseTargets(nil) returned error: %v", err)
+	}
+	if len(targets) != 1 || *targets[0] != (Target{Proto: "http", Port: 80}) {
+		t.Fatalf("ParseTargets(nil) = %+v, want one default http:80 target", targets)
+	}
+}
+
+func TestParseTargetsPropagatesFirstError(t *testing.T) {
+	_, err := ParseTargets([]string{"http:8080", "bad:port"})
+	if err == nil {
+		t.Fatal("ParseTargets expected an error for an invalid spec, got nil")
+	}
+}
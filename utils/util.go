@@ -2,18 +2,23 @@ package utils
 
 import (
 	"encoding/json"
-	"fmt"
 	"net"
 	"os"
 	"path/filepath"
 	"runtime"
 
 	"github.com/mitchellh/go-homedir"
+	"github.com/triplemcoder14/domainforge/internal/dferr"
 )
 
 type Config struct {
 	QulesAdmin   string `json:"qules_admin"`
 	AdminAddress string `json:"admin_address"`
+	// Interface restricts broadcasting to a single named interface when
+	// non-empty (see GetLocalIPs). Empty means "every suitable interface".
+	Interface string `json:"interface,omitempty"`
+	// IncludeIPv6 additionally advertises global-scope IPv6 addresses.
+	IncludeIPv6 bool `json:"include_ipv6,omitempty"`
 }
 
 func DefaultConfig() *Config {
@@ -79,28 +84,81 @@ func ReadConfig() (*Config, error) {
 
 	var cfg Config
 	if err := json.Unmarshal(data, &cfg); err != nil {
-		return DefaultConfig(), err
+		return DefaultConfig(), dferr.Wrap(err, dferr.ConfigInvalid, "config.json is not valid JSON")
 	}
 
 	return &cfg, nil
 }
 
-func GetLocalIP() (string, error) {
-	addrs, err := net.InterfaceAddrs()
+// LocalAddr is one global-scope address found on one network interface.
+type LocalAddr struct {
+	Interface string
+	IP        string
+	IsIPv6    bool
+}
+
+// IPOptions filters GetLocalIPs.
+type IPOptions struct {
+	// Interface restricts the result to one named interface. Empty means
+	// every interface is considered.
+	Interface string
+	// IncludeIPv6 additionally returns global-scope IPv6 addresses. By
+	// default only IPv4 is returned, matching prior behavior.
+	IncludeIPv6 bool
+}
+
+// GetLocalIPs returns every global-scope address (optionally IPv6 too)
+// across every suitable interface, grouped by the interface it came from,
+// so a multi-homed host (VPN + wifi, a docker bridge, dual-stack v4/v6)
+// can be advertised on all of its segments instead of just the first one
+// net.InterfaceAddrs happens to report. Failure to list interfaces, or
+// finding nothing suitable, is NetworkTransient: a host can briefly have
+// no usable interfaces (link flapping, a VPN reconnecting) without that
+// being a permanent condition.
+func GetLocalIPs(opts IPOptions) ([]LocalAddr, error) {
+	ifaces, err := net.Interfaces()
 	if err != nil {
-		return "", err
+		return nil, dferr.Wrap(err, dferr.NetworkTransient, "failed to list network interfaces")
 	}
-	for _, addr := range addrs {
-		var ip net.IP
-		switch v := addr.(type) {
-		case *net.IPNet:
-			ip = v.IP
-		case *net.IPAddr:
-			ip = v.IP
+
+	var found []LocalAddr
+	for _, iface := range ifaces {
+		if opts.Interface != "" && iface.Name != opts.Interface {
+			continue
 		}
-		if ip != nil && !ip.IsLoopback() && ip.To4() != nil {
-			return ip.String(), nil
+		if iface.Flags&net.FlagUp == 0 {
+			continue
 		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			var ip net.IP
+			switch v := addr.(type) {
+			case *net.IPNet:
+				ip = v.IP
+			case *net.IPAddr:
+				ip = v.IP
+			}
+			if ip == nil || ip.IsLoopback() || !ip.IsGlobalUnicast() {
+				continue
+			}
+
+			if ip.To4() != nil {
+				found = append(found, LocalAddr{Interface: iface.Name, IP: ip.String()})
+				continue
+			}
+			if opts.IncludeIPv6 {
+				found = append(found, LocalAddr{Interface: iface.Name, IP: ip.String(), IsIPv6: true})
+			}
+		}
+	}
+
+	if len(found) == 0 {
+		return nil, dferr.New(dferr.NetworkTransient, "no suitable local IP address found")
 	}
-	return "", fmt.Errorf("no suitable local IP address found")
+	return found, nil
 }